@@ -0,0 +1,84 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+var processStart = time.Now()
+
+// debugInfo is the payload served by /debug/info, describing the
+// running instance well enough for an operator to verify which
+// capabilities a given boatmcp server advertises.
+type debugInfo struct {
+	GoVersion    string     `json:"goVersion"`
+	NumCPU       int        `json:"numCPU"`
+	NumGoroutine int        `json:"numGoroutine"`
+	Hostname     string     `json:"hostname"`
+	UID          int        `json:"uid"`
+	GID          int        `json:"gid"`
+	StartedAt    time.Time  `json:"startedAt"`
+	Uptime       string     `json:"uptime"`
+	BuildInfo    *buildInfo `json:"buildInfo,omitempty"`
+	Tools        []Tool     `json:"tools"`
+	Resources    []Resource `json:"resources"`
+}
+
+type buildInfo struct {
+	GoVersion string `json:"goVersion"`
+	Path      string `json:"path"`
+	Main      string `json:"main"`
+}
+
+// debugHandler returns a handler for /debug/info that is only served
+// when authorized is true, or when the request carries the configured
+// bearer token.
+func debugHandler(mcp *mcpServer, authorized bool, token string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !authorized && !bearerMatches(r, token) {
+			http.NotFound(w, r)
+			return
+		}
+
+		info := debugInfo{
+			GoVersion:    runtime.Version(),
+			NumCPU:       runtime.NumCPU(),
+			NumGoroutine: runtime.NumGoroutine(),
+			UID:          os.Getuid(),
+			GID:          os.Getgid(),
+			StartedAt:    processStart,
+			Uptime:       time.Since(processStart).String(),
+			Tools:        mcp.safeListTools(),
+			Resources:    mcp.safeListResources(),
+		}
+
+		if host, err := os.Hostname(); err == nil {
+			info.Hostname = host
+		}
+
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			info.BuildInfo = &buildInfo{GoVersion: bi.GoVersion, Path: bi.Path, Main: bi.Main.Path}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(info)
+	}
+}
+
+func bearerMatches(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+	const prefix = "Bearer "
+	got := r.Header.Get("Authorization")
+	if !strings.HasPrefix(got, prefix) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(got[len(prefix):]), []byte(token)) == 1
+}
@@ -1,17 +1,114 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 func handler(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintf(w, "Hello, from your Go sample application!")
 }
 
+func shutdownTimeout() time.Duration {
+	if v := os.Getenv("SHUTDOWN_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+		log.Printf("ignoring invalid SHUTDOWN_TIMEOUT %q, using default", v)
+	}
+	return 30 * time.Second
+}
+
+// listenAddr resolves the address to bind, preferring explicit flags
+// over the HTTP_ADDR and PORT environment conventions, falling back to
+// :8080.
+func listenAddr(httpFlag, portFlag string) string {
+	if httpFlag != "" {
+		return httpFlag
+	}
+	if v := os.Getenv("HTTP_ADDR"); v != "" {
+		return v
+	}
+	if portFlag != "" {
+		return ":" + portFlag
+	}
+	if v := os.Getenv("PORT"); v != "" {
+		return ":" + v
+	}
+	return ":8080"
+}
+
+func newMux(mcp *mcpServer, staticDir, templatesDir string, debugAuthorized bool, debugToken string) (http.Handler, error) {
+	rnd, err := newRenderer(templatesDir)
+	if err != nil {
+		return nil, fmt.Errorf("parsing templates: %w", err)
+	}
+	reqLog := newRequestLog(100)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", rnd.indexHandler(mcp))
+	mux.HandleFunc("/logs", rnd.logsHandler(reqLog))
+	mux.HandleFunc("/healthz", handler)
+	mux.HandleFunc("/mcp", mcp.mcpHandler)
+	mux.HandleFunc("/debug/info", debugHandler(mcp, debugAuthorized, debugToken))
+
+	if staticDir != "" {
+		fs := http.FileServer(http.Dir(staticDir))
+		mux.Handle("/static/", http.StripPrefix("/static/", fs))
+	}
+
+	return loggingMiddleware(mux, reqLog), nil
+}
+
 func main() {
-	http.HandleFunc("/", handler)
-	fmt.Println("Go server listening on :8080")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	httpAddr := flag.String("http", "", "address to listen on, e.g. :8080 (overrides HTTP_ADDR/-port/PORT)")
+	port := flag.String("port", "", "convenience port flag, equivalent to -http :PORT (overrides PORT)")
+	staticDir := flag.String("static", "", "directory of static assets to serve under /static/")
+	templatesDir := flag.String("templates", "", "directory of view templates to use instead of the embedded ones")
+	debugFlag := flag.Bool("debug", false, "expose /debug/info without requiring a bearer token")
+	flag.Parse()
+
+	addr := listenAddr(*httpAddr, *port)
+	mcp := &mcpServer{}
+	mux, err := newMux(mcp, *staticDir, *templatesDir, *debugFlag, os.Getenv("DEBUG_TOKEN"))
+	if err != nil {
+		log.Fatalf("setting up routes: %v", err)
+	}
+
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		fmt.Printf("Go server listening on %s\n", addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("listen: %v", err)
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+
+	timeout := shutdownTimeout()
+	log.Printf("shutting down, draining in-flight requests (timeout %s)", timeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Fatalf("graceful shutdown failed: %v", err)
+	}
+
+	// srv.Shutdown does not wait for hijacked connections, so the
+	// in-flight /mcp sessions have to be drained separately.
+	mcp.Shutdown(ctx)
+
+	log.Println("shutdown complete")
 }
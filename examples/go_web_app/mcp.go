@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// jsonRPCRequest is a single JSON-RPC 2.0 request object as sent by an
+// MCP client over the hijacked connection.
+type jsonRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// jsonRPCResponse is a single JSON-RPC 2.0 response object. Exactly one
+// of Result or Error is set.
+type jsonRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *jsonRPCError   `json:"error,omitempty"`
+}
+
+type jsonRPCError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+const (
+	jsonRPCParseError     = -32700
+	jsonRPCInvalidRequest = -32600
+	jsonRPCMethodNotFound = -32601
+	jsonRPCInvalidParams  = -32602
+	jsonRPCInternalError  = -32603
+)
+
+// Tool describes a single MCP tool as advertised by tools/list.
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+// ToolRegistry is implemented by anything that can enumerate and invoke
+// MCP tools. The JSON-RPC loop in serveMCP dispatches tools/list and
+// tools/call against it.
+type ToolRegistry interface {
+	ListTools() []Tool
+	CallTool(name string, args json.RawMessage) (interface{}, error)
+}
+
+// Resource describes a single MCP resource as advertised by
+// resources/list.
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceProvider is implemented by anything that can enumerate and
+// read MCP resources.
+type ResourceProvider interface {
+	ListResources() []Resource
+	ReadResource(uri string) (interface{}, error)
+}
+
+// mcpServer holds the pluggable registry/provider pair used to answer
+// JSON-RPC calls on the hijacked connection, plus the bookkeeping
+// needed to drain active sessions on shutdown.
+type mcpServer struct {
+	tools     ToolRegistry
+	resources ResourceProvider
+
+	wg    sync.WaitGroup
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+func (s *mcpServer) trackConn(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conns == nil {
+		s.conns = make(map[net.Conn]struct{})
+	}
+	s.conns[conn] = struct{}{}
+}
+
+func (s *mcpServer) untrackConn(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, conn)
+}
+
+func (s *mcpServer) closeActiveConns() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+// Shutdown waits for in-flight MCP sessions to finish on their own. If
+// ctx is done first, it force-closes any still-hijacked connections so
+// the sessions unblock and the wait can complete; http.Server.Shutdown
+// does not know about hijacked connections, so mcpServer has to drain
+// them itself.
+func (s *mcpServer) Shutdown(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.closeActiveConns()
+		<-done
+	}
+}
+
+// safeListTools returns the registered tools, or an empty slice if no
+// ToolRegistry is configured.
+func (s *mcpServer) safeListTools() []Tool {
+	if s.tools == nil {
+		return []Tool{}
+	}
+	return s.tools.ListTools()
+}
+
+// safeListResources returns the registered resources, or an empty
+// slice if no ResourceProvider is configured.
+func (s *mcpServer) safeListResources() []Resource {
+	if s.resources == nil {
+		return []Resource{}
+	}
+	return s.resources.ListResources()
+}
+
+// mcpHandler hijacks the underlying connection and runs a JSON-RPC 2.0
+// loop over it until the client disconnects or sends a malformed frame
+// it can't recover from.
+func (s *mcpServer) mcpHandler(w http.ResponseWriter, r *http.Request) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "connection does not support hijacking", http.StatusInternalServerError)
+		return
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		http.Error(w, "hijack failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer conn.Close()
+
+	s.wg.Add(1)
+	defer s.wg.Done()
+	s.trackConn(conn)
+	defer s.untrackConn(conn)
+
+	// The client already sent an HTTP request to reach this handler; ack
+	// it with a 200 so it knows to treat the rest of the connection as a
+	// raw, long-lived JSON-RPC stream rather than waiting on an HTTP
+	// response. The connection stays open for the life of the session,
+	// so no Connection header is written here.
+	rw.WriteString("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\n\r\n")
+	rw.Flush()
+
+	s.serveJSONRPC(rw.Reader, rw.Writer)
+}
+
+// serveJSONRPC reads newline-delimited JSON-RPC requests from r and
+// writes responses to w, flushing after each one.
+func (s *mcpServer) serveJSONRPC(r *bufio.Reader, w *bufio.Writer) {
+	dec := json.NewDecoder(r)
+	for {
+		var req jsonRPCRequest
+		if err := dec.Decode(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			log.Printf("mcp: decode error: %v", err)
+			writeJSONRPCError(w, nil, jsonRPCParseError, "parse error")
+			return
+		}
+
+		resp := s.dispatch(&req)
+		if err := writeJSONRPCResponse(w, resp); err != nil {
+			log.Printf("mcp: write error: %v", err)
+			return
+		}
+	}
+}
+
+// dispatch routes a single request to the appropriate method handler
+// and always returns a response, converting handler errors into
+// JSON-RPC error objects.
+func (s *mcpServer) dispatch(req *jsonRPCRequest) *jsonRPCResponse {
+	switch req.Method {
+	case "initialize":
+		return s.handleInitialize(req)
+	case "tools/list":
+		return s.handleToolsList(req)
+	case "tools/call":
+		return s.handleToolsCall(req)
+	case "resources/list":
+		return s.handleResourcesList(req)
+	case "resources/read":
+		return s.handleResourcesRead(req)
+	default:
+		return errorResponse(req.ID, jsonRPCMethodNotFound, "method not found: "+req.Method)
+	}
+}
+
+func (s *mcpServer) handleInitialize(req *jsonRPCRequest) *jsonRPCResponse {
+	return resultResponse(req.ID, map[string]interface{}{
+		"protocolVersion": "2024-11-05",
+		"serverInfo": map[string]string{
+			"name":    "boatmcp",
+			"version": "0.1.0",
+		},
+	})
+}
+
+func (s *mcpServer) handleToolsList(req *jsonRPCRequest) *jsonRPCResponse {
+	return resultResponse(req.ID, map[string]interface{}{"tools": s.safeListTools()})
+}
+
+func (s *mcpServer) handleToolsCall(req *jsonRPCRequest) *jsonRPCResponse {
+	if s.tools == nil {
+		return errorResponse(req.ID, jsonRPCInternalError, "no tool registry configured")
+	}
+
+	var params struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return errorResponse(req.ID, jsonRPCInvalidParams, "invalid params: "+err.Error())
+	}
+
+	result, err := s.tools.CallTool(params.Name, params.Arguments)
+	if err != nil {
+		return errorResponse(req.ID, jsonRPCInternalError, err.Error())
+	}
+	return resultResponse(req.ID, result)
+}
+
+func (s *mcpServer) handleResourcesList(req *jsonRPCRequest) *jsonRPCResponse {
+	return resultResponse(req.ID, map[string]interface{}{"resources": s.safeListResources()})
+}
+
+func (s *mcpServer) handleResourcesRead(req *jsonRPCRequest) *jsonRPCResponse {
+	if s.resources == nil {
+		return errorResponse(req.ID, jsonRPCInternalError, "no resource provider configured")
+	}
+
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return errorResponse(req.ID, jsonRPCInvalidParams, "invalid params: "+err.Error())
+	}
+
+	result, err := s.resources.ReadResource(params.URI)
+	if err != nil {
+		return errorResponse(req.ID, jsonRPCInternalError, err.Error())
+	}
+	return resultResponse(req.ID, result)
+}
+
+func resultResponse(id json.RawMessage, result interface{}) *jsonRPCResponse {
+	return &jsonRPCResponse{JSONRPC: "2.0", ID: id, Result: result}
+}
+
+func errorResponse(id json.RawMessage, code int, message string) *jsonRPCResponse {
+	return &jsonRPCResponse{JSONRPC: "2.0", ID: id, Error: &jsonRPCError{Code: code, Message: message}}
+}
+
+func writeJSONRPCResponse(w *bufio.Writer, resp *jsonRPCResponse) error {
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func writeJSONRPCError(w *bufio.Writer, id json.RawMessage, code int, message string) {
+	_ = writeJSONRPCResponse(w, errorResponse(id, code, message))
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+//go:embed views/*.html
+var embeddedViews embed.FS
+
+// renderer parses the base layout plus per-page partials once at
+// startup and executes the "layout" template for each page, mirroring
+// the template.ParseFiles + ExecuteTemplate pattern used elsewhere in
+// these examples.
+type renderer struct {
+	pages map[string]*template.Template
+}
+
+// newRenderer builds a renderer from either the embedded views so the
+// binary stays single-file, or from templatesDir on disk when set
+// (useful while iterating on templates during development).
+func newRenderer(templatesDir string) (*renderer, error) {
+	var viewsFS fs.FS
+	if templatesDir != "" {
+		viewsFS = os.DirFS(templatesDir)
+	} else {
+		sub, err := fs.Sub(embeddedViews, "views")
+		if err != nil {
+			return nil, err
+		}
+		viewsFS = sub
+	}
+
+	pages := map[string]*template.Template{}
+	for _, page := range []string{"index.html", "logs.html"} {
+		tmpl, err := template.ParseFS(viewsFS, "layout.html", "header.html", "footer.html", page)
+		if err != nil {
+			return nil, err
+		}
+		pages[page] = tmpl
+	}
+
+	return &renderer{pages: pages}, nil
+}
+
+func (rnd *renderer) render(w http.ResponseWriter, page string, data interface{}) {
+	tmpl, ok := rnd.pages[page]
+	if !ok {
+		http.Error(w, "unknown template: "+page, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.ExecuteTemplate(w, "layout", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (rnd *renderer) indexHandler(mcp *mcpServer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data := struct {
+			Tools     []Tool
+			Resources []Resource
+		}{
+			Tools:     mcp.safeListTools(),
+			Resources: mcp.safeListResources(),
+		}
+		rnd.render(w, "index.html", data)
+	}
+}
+
+func (rnd *renderer) logsHandler(reqLog *requestLog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data := struct{ Entries []logEntry }{Entries: reqLog.recent()}
+		rnd.render(w, "logs.html", data)
+	}
+}
+
+// logEntry captures a single request/response pair for display on the
+// /logs page.
+type logEntry struct {
+	Time     time.Time
+	Method   string
+	Path     string
+	Status   int
+	Duration time.Duration
+}
+
+// requestLog is a fixed-size ring buffer of the most recent logEntry
+// values, safe for concurrent use by the logging middleware.
+type requestLog struct {
+	mu      sync.Mutex
+	entries []logEntry
+	max     int
+}
+
+func newRequestLog(max int) *requestLog {
+	return &requestLog{max: max}
+}
+
+func (l *requestLog) record(entry logEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, entry)
+	if len(l.entries) > l.max {
+		l.entries = l.entries[len(l.entries)-l.max:]
+	}
+}
+
+// recent returns the logged entries newest-first.
+func (l *requestLog) recent() []logEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	out := make([]logEntry, len(l.entries))
+	for i, e := range l.entries {
+		out[len(l.entries)-1-i] = e
+	}
+	return out
+}
+
+// statusRecorder wraps an http.ResponseWriter to capture the status
+// code written by the wrapped handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Hijack forwards to the wrapped ResponseWriter so handlers downstream
+// of loggingMiddleware (e.g. mcpHandler) can still take over the raw
+// connection.
+func (s *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("underlying ResponseWriter does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
+// loggingMiddleware records a logEntry for every request handled by
+// next into reqLog.
+func loggingMiddleware(next http.Handler, reqLog *requestLog) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		reqLog.record(logEntry{
+			Time:     start,
+			Method:   r.Method,
+			Path:     r.URL.Path,
+			Status:   rec.status,
+			Duration: time.Since(start),
+		})
+	})
+}